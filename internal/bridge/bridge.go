@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+const defaultUpdateCheckPeriod = 6 * time.Hour
+
+// LegacyVault is the subset of the pre-FileCookieJar settings vault that
+// Bridge imports cookies from on first run.
+type LegacyVault interface {
+	GetCookies() map[string][]*http.Cookie
+}
+
+// Bridge wires together the long-running subsystems started at bridge
+// startup: the auto-updater, its poll loop, and the persistent cookie jar.
+type Bridge struct {
+	Updater    Updater
+	UpdateLoop *UpdateLoop
+	CookieJar  *FileCookieJar
+}
+
+// New constructs the production Bridge: a real updater.Updater, backed by
+// downloader and installer, polled by an UpdateLoop that reports
+// irrecoverable failures through reporter; and an encrypted FileCookieJar
+// under locations, seeded from legacy on first run.
+func New(
+	repository string,
+	downloader updater.Downloader,
+	installer updater.Installer,
+	currentVersion *semver.Version,
+	rolloutSeed string,
+	reporter Reporter,
+	locations configLocationProvider,
+	cookieJarKey []byte,
+	legacy LegacyVault,
+) (*Bridge, error) {
+	upd := updater.New(repository, downloader, installer, currentVersion, rolloutSeed)
+
+	cookieJar, err := NewFileCookieJar(locations, cookieJarKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookie jar: %w", err)
+	}
+
+	if legacy != nil {
+		if err := cookieJar.ImportLegacyCookies(legacy.GetCookies()); err != nil {
+			return nil, fmt.Errorf("failed to import legacy cookies: %w", err)
+		}
+	}
+
+	return &Bridge{
+		Updater:    upd,
+		UpdateLoop: NewUpdateLoop(upd, downloader, reporter, defaultUpdateCheckPeriod),
+		CookieJar:  cookieJar,
+	}, nil
+}
+
+// Start begins the bridge's background subsystems, including the
+// update-poll loop.
+func (b *Bridge) Start(ctx context.Context) {
+	b.UpdateLoop.Start(ctx)
+}
@@ -0,0 +1,252 @@
+package bridge
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const cookieJarFileName = "cookies.jar"
+
+// configLocationProvider is the subset of Locations that FileCookieJar needs;
+// satisfied by both the real bridge Locations and TestLocationsProvider.
+type configLocationProvider interface {
+	UserConfig() string
+}
+
+// FileCookieJar is an http.CookieJar that persists cookies to disk, encrypted
+// with a key derived from the system keychain, so API session cookies
+// survive a bridge restart instead of forcing re-auth.
+type FileCookieJar struct {
+	path string
+	key  []byte
+
+	lock    sync.Mutex
+	cookies map[string][]*http.Cookie
+}
+
+// NewFileCookieJar opens (or creates) the encrypted cookie jar for this
+// install under locations.UserConfig(), decrypting with key. Expired cookies
+// read from disk are never returned by Cookies and are dropped on the next
+// save.
+func NewFileCookieJar(locations configLocationProvider, key []byte) (*FileCookieJar, error) {
+	jar := &FileCookieJar{
+		path:    filepath.Join(locations.UserConfig(), cookieJarFileName),
+		key:     key,
+		cookies: make(map[string][]*http.Cookie),
+	}
+
+	if err := jar.load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load cookie jar: %w", err)
+	}
+
+	return jar, nil
+}
+
+func (jar *FileCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	jar.lock.Lock()
+	defer jar.lock.Unlock()
+
+	jar.cookies[u.Host] = stampExpiry(cookies)
+
+	// Persisting is best-effort: losing the jar only costs a re-auth, so it
+	// must never block the caller that just set the cookies.
+	_ = jar.save()
+}
+
+// stampExpiry resolves each cookie's effective expiry to an absolute
+// timestamp in Expires, computing it from a positive Max-Age when the server
+// sent Max-Age without Expires (the common case: Go's http.Cookie leaves
+// Expires zero for "Set-Cookie: …; Max-Age=3600").
+func stampExpiry(cookies []*http.Cookie) []*http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Expires.IsZero() && cookie.MaxAge > 0 {
+			cookie.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+	}
+
+	return cookies
+}
+
+func (jar *FileCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	jar.lock.Lock()
+	defer jar.lock.Unlock()
+
+	var valid []*http.Cookie
+
+	for _, cookie := range jar.cookies[u.Host] {
+		if !isExpiredCookie(cookie) {
+			valid = append(valid, cookie)
+		}
+	}
+
+	return valid
+}
+
+// ImportLegacyCookies seeds the jar from the legacy vault's cookie field on
+// first run, then persists them under the new encrypted store. It is a
+// no-op once a jar file already exists on disk.
+func (jar *FileCookieJar) ImportLegacyCookies(legacy map[string][]*http.Cookie) error {
+	jar.lock.Lock()
+	defer jar.lock.Unlock()
+
+	if _, err := os.Stat(jar.path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat cookie jar: %w", err)
+	}
+
+	for host, cookies := range legacy {
+		legacy[host] = stampExpiry(cookies)
+	}
+
+	jar.cookies = legacy
+
+	return jar.save()
+}
+
+func isExpiredCookie(cookie *http.Cookie) bool {
+	if cookie.MaxAge < 0 {
+		return true
+	}
+
+	return !cookie.Expires.IsZero() && cookie.Expires.Before(time.Now())
+}
+
+func (jar *FileCookieJar) save() error {
+	pruned := make(map[string][]*http.Cookie, len(jar.cookies))
+
+	for host, cookies := range jar.cookies {
+		var keep []*http.Cookie
+
+		for _, cookie := range cookies {
+			if !isExpiredCookie(cookie) {
+				keep = append(keep, cookie)
+			}
+		}
+
+		if len(keep) > 0 {
+			pruned[host] = keep
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(pruned); err != nil {
+		return fmt.Errorf("failed to encode cookies: %w", err)
+	}
+
+	ciphertext, err := encryptCookieJar(jar.key, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt cookies: %w", err)
+	}
+
+	tmp := jar.path + ".tmp"
+	if err := os.WriteFile(tmp, ciphertext, 0o600); err != nil {
+		return fmt.Errorf("failed to write cookie jar: %w", err)
+	}
+
+	if err := os.Rename(tmp, jar.path); err != nil {
+		return fmt.Errorf("failed to replace cookie jar: %w", err)
+	}
+
+	return nil
+}
+
+func (jar *FileCookieJar) load() error {
+	ciphertext, err := os.ReadFile(jar.path)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := decryptCookieJar(jar.key, ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt cookie jar (wrong key or corrupt file): %w", err)
+	}
+
+	var cookies map[string][]*http.Cookie
+	if err := gob.NewDecoder(bytes.NewReader(plaintext)).Decode(&cookies); err != nil {
+		return fmt.Errorf("failed to decode cookie jar: %w", err)
+	}
+
+	jar.cookies = cookies
+
+	return nil
+}
+
+func encryptCookieJar(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptCookieJar(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("cookie jar file is corrupt: ciphertext shorter than nonce")
+	}
+
+	nonce, data := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, data, nil)
+}
+
+// MemoryCookieJar is a plain in-memory http.CookieJar for callers that don't
+// need cookies to survive a restart.
+type MemoryCookieJar struct {
+	lock    sync.Mutex
+	cookies map[string][]*http.Cookie
+}
+
+// NewMemoryCookieJar returns an empty, non-persistent cookie jar.
+func NewMemoryCookieJar() *MemoryCookieJar {
+	return &MemoryCookieJar{
+		cookies: make(map[string][]*http.Cookie),
+	}
+}
+
+func (jar *MemoryCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	jar.lock.Lock()
+	defer jar.lock.Unlock()
+
+	jar.cookies[u.Host] = cookies
+}
+
+func (jar *MemoryCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	jar.lock.Lock()
+	defer jar.lock.Unlock()
+
+	return jar.cookies[u.Host]
+}
@@ -0,0 +1,163 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testCookieJarKey(b byte) []byte {
+	return []byte{
+		b, b, b, b, b, b, b, b,
+		b, b, b, b, b, b, b, b,
+		b, b, b, b, b, b, b, b,
+		b, b, b, b, b, b, b, b,
+	}
+}
+
+func TestFileCookieJarRoundTrip(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+	key := testCookieJarKey('a')
+
+	jar, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar: %v", err)
+	}
+
+	u := &url.URL{Scheme: "https", Host: "api.protonmail.ch"}
+	jar.SetCookies(u, []*http.Cookie{{Name: "Session-Id", Value: "abc123"}})
+
+	reopened, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar (reopen): %v", err)
+	}
+
+	cookies := reopened.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Value != "abc123" {
+		t.Fatalf("expected cookie to survive a reload, got %+v", cookies)
+	}
+}
+
+func TestFileCookieJarHonorsMaxAgeOnLoad(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+	key := testCookieJarKey('b')
+
+	jar, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar: %v", err)
+	}
+
+	u := &url.URL{Scheme: "https", Host: "api.protonmail.ch"}
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "Expired-By-MaxAge", Value: "x", MaxAge: -1},
+		{Name: "Fresh-By-MaxAge", Value: "y", MaxAge: 3600},
+	})
+
+	reopened, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar (reopen): %v", err)
+	}
+
+	cookies := reopened.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != "Fresh-By-MaxAge" {
+		t.Fatalf("expected only the non-expired Max-Age cookie to survive, got %+v", cookies)
+	}
+}
+
+func TestFileCookieJarCorruptFile(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+
+	path := filepath.Join(locations.UserConfig(), cookieJarFileName)
+	if err := os.WriteFile(path, []byte("not a valid encrypted jar"), 0o600); err != nil {
+		t.Fatalf("failed to seed corrupt jar file: %v", err)
+	}
+
+	if _, err := NewFileCookieJar(locations, testCookieJarKey('c')); err == nil {
+		t.Fatalf("expected a corrupt jar file to fail to load")
+	}
+}
+
+func TestFileCookieJarWrongKey(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+
+	jar, err := NewFileCookieJar(locations, testCookieJarKey('d'))
+	if err != nil {
+		t.Fatalf("NewFileCookieJar: %v", err)
+	}
+
+	jar.SetCookies(&url.URL{Host: "api.protonmail.ch"}, []*http.Cookie{{Name: "s", Value: "1"}})
+
+	if _, err := NewFileCookieJar(locations, testCookieJarKey('e')); err == nil {
+		t.Fatalf("expected opening the jar with the wrong key to fail")
+	}
+}
+
+func TestFileCookieJarPartialWriteFailsToLoad(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+	key := testCookieJarKey('f')
+
+	jar, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar: %v", err)
+	}
+
+	jar.SetCookies(&url.URL{Host: "api.protonmail.ch"}, []*http.Cookie{{Name: "s", Value: "1"}})
+
+	path := filepath.Join(locations.UserConfig(), cookieJarFileName)
+
+	full, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read jar file: %v", err)
+	}
+
+	if err := os.WriteFile(path, full[:len(full)/2], 0o600); err != nil {
+		t.Fatalf("failed to truncate jar file: %v", err)
+	}
+
+	if _, err := NewFileCookieJar(locations, key); err == nil {
+		t.Fatalf("expected a truncated (partial-write) jar file to fail to load cleanly")
+	}
+}
+
+func TestFileCookieJarImportLegacyCookiesOnlyOnFirstRun(t *testing.T) {
+	locations := NewTestLocationsProvider(t.TempDir())
+	key := testCookieJarKey('g')
+
+	jar, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar: %v", err)
+	}
+
+	legacy := map[string][]*http.Cookie{
+		"api.protonmail.ch": {{Name: "Legacy-Session", Value: "imported"}},
+	}
+
+	if err := jar.ImportLegacyCookies(legacy); err != nil {
+		t.Fatalf("ImportLegacyCookies: %v", err)
+	}
+
+	reopened, err := NewFileCookieJar(locations, key)
+	if err != nil {
+		t.Fatalf("NewFileCookieJar (reopen): %v", err)
+	}
+
+	cookies := reopened.Cookies(&url.URL{Host: "api.protonmail.ch"})
+	if len(cookies) != 1 || cookies[0].Value != "imported" {
+		t.Fatalf("expected the legacy cookie to be imported, got %+v", cookies)
+	}
+
+	// A second import after the jar already exists on disk must not
+	// clobber cookies set since the first run.
+	reopened.SetCookies(&url.URL{Host: "api.protonmail.ch"}, []*http.Cookie{{Name: "New-Session", Value: "current"}})
+
+	if err := reopened.ImportLegacyCookies(legacy); err != nil {
+		t.Fatalf("ImportLegacyCookies (second run): %v", err)
+	}
+
+	cookies = reopened.Cookies(&url.URL{Host: "api.protonmail.ch"})
+	if len(cookies) != 1 || cookies[0].Name != "New-Session" {
+		t.Fatalf("expected the second ImportLegacyCookies to be a no-op, got %+v", cookies)
+	}
+}
@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -36,7 +37,7 @@ func NewMocks(tb testing.TB, version, minAuto *semver.Version) *Mocks {
 		TLSReporter: mocks.NewMockTLSReporter(ctl),
 		TLSIssueCh:  make(chan struct{}),
 
-		Updater:     NewTestUpdater(version, minAuto),
+		Updater:     NewTestUpdater(tb, version, minAuto),
 		Autostarter: mocks.NewMockAutostarter(ctl),
 
 		CrashHandler: mocks.NewMockPanicHandler(ctl),
@@ -122,63 +123,268 @@ func (provider *TestLocationsProvider) UserCache() string {
 }
 
 type TestUpdater struct {
-	latest   updater.VersionInfoLegacy
-	releases updater.VersionInfo
-	lock     sync.RWMutex
+	// rolloutID is this install's stable bucket in [0, 1), used to decide
+	// whether it falls inside a release's staged rollout window.
+	rolloutID float64
+
+	// currentVersion is the version this install is running, used to
+	// validate channel switches.
+	currentVersion *semver.Version
+	allowDowngrade bool
+
+	// channel is the channel GetVersionInfo reports on; GetVersionInfoLegacy
+	// reports on the channel passed to it instead.
+	channel updater.Channel
+
+	latest   map[updater.Channel]updater.VersionInfoLegacy
+	releases map[updater.Channel]updater.VersionInfo
+
+	// rolloutProportion is the single source of truth for each channel's
+	// rollout proportion; latest/releases entries never carry their own,
+	// so the two can't drift apart.
+	rolloutProportion map[updater.Channel]float64
+
+	installErr   error
+	installCount int
+
+	// scheduler coalesces bursts of GetVersionInfo calls the way the real
+	// updater's CheckScheduler does.
+	scheduler *updater.CheckScheduler
+
+	lock sync.RWMutex
 }
 
-func NewTestUpdater(version, minAuto *semver.Version) *TestUpdater {
-	return &TestUpdater{
-		latest: updater.VersionInfoLegacy{
-			Version: version,
-			MinAuto: minAuto,
+func NewTestUpdater(tb testing.TB, version, minAuto *semver.Version) *TestUpdater {
+	testUpdater := &TestUpdater{
+		rolloutID: updater.RolloutID(tb.Name()),
+
+		currentVersion: version,
+		channel:        updater.StableChannel,
 
-			RolloutProportion: 1.0,
+		latest: map[updater.Channel]updater.VersionInfoLegacy{
+			updater.StableChannel: {
+				Version: version,
+				MinAuto: minAuto,
+			},
 		},
+		releases: map[updater.Channel]updater.VersionInfo{
+			updater.StableChannel: {
+				Release: updater.Release{
+					Version: version,
+					MinAuto: minAuto,
+				},
+			},
+		},
+		rolloutProportion: map[updater.Channel]float64{
+			updater.StableChannel: 1.0,
+		},
+	}
+
+	testUpdater.scheduler = updater.NewCheckScheduler(testUpdater.getVersionInfo)
+
+	return testUpdater
+}
+
+// SetThrottle changes the window within which GetVersionInfo calls are
+// coalesced into one.
+func (testUpdater *TestUpdater) SetThrottle(d time.Duration) {
+	testUpdater.scheduler.SetThrottle(d)
+}
+
+// FlushPending fires any coalesced GetVersionInfo call immediately, instead
+// of waiting for the throttle window to elapse, so tests stay deterministic.
+func (testUpdater *TestUpdater) FlushPending() {
+	testUpdater.scheduler.FlushPending(nil)
+}
+
+// CheckCallCount reports how many times GetVersionInfo actually ran its
+// check, as opposed to being coalesced into an existing pending check.
+func (testUpdater *TestUpdater) CheckCallCount() int {
+	return testUpdater.scheduler.CheckCallCount()
+}
+
+// SetRolloutProportion sets the fraction of installs that are eligible for
+// the current channel's release. It overwrites the current proportion, so it
+// can also narrow the cohort; AdvanceRollout only ever widens it.
+func (testUpdater *TestUpdater) SetRolloutProportion(p float64) {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	testUpdater.rolloutProportion[testUpdater.channel] = p
+}
+
+// AdvanceRollout widens the rollout cohort to p, as if the server had
+// progressed a staged rollout further. It never shrinks the cohort.
+func (testUpdater *TestUpdater) AdvanceRollout(p float64) {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	if p > testUpdater.rolloutProportion[testUpdater.channel] {
+		testUpdater.rolloutProportion[testUpdater.channel] = p
+	}
+}
+
+// EligibleForRollout reports whether this install falls inside the staged
+// rollout window of the current channel's release.
+func (testUpdater *TestUpdater) EligibleForRollout() bool {
+	testUpdater.lock.RLock()
+	defer testUpdater.lock.RUnlock()
+
+	return testUpdater.rolloutID < testUpdater.rolloutProportion[testUpdater.channel]
+}
+
+// RolloutID returns this install's stable bucket in [0, 1).
+func (testUpdater *TestUpdater) RolloutID() float64 {
+	testUpdater.lock.RLock()
+	defer testUpdater.lock.RUnlock()
+
+	return testUpdater.rolloutID
+}
+
+// SetAllowDowngrade toggles whether SwitchChannel may move to a channel whose
+// latest release is older than the current version.
+func (testUpdater *TestUpdater) SetAllowDowngrade(allow bool) {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	testUpdater.allowDowngrade = allow
+}
+
+// SwitchChannel moves the install to ch, refusing to do so if ch's latest
+// release is older than the current version (unless AllowDowngrade was set)
+// or if the current version is below ch's MinAuto.
+func (testUpdater *TestUpdater) SwitchChannel(_ context.Context, ch updater.Channel) error {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	target, ok := testUpdater.releases[ch]
+	if !ok {
+		return fmt.Errorf("no version configured for channel %q", ch)
+	}
+
+	if !testUpdater.allowDowngrade && target.Version != nil && testUpdater.currentVersion != nil && target.Version.LessThan(testUpdater.currentVersion) {
+		return fmt.Errorf("refusing to switch to channel %q: %s would be a downgrade from %s", ch, target.Version, testUpdater.currentVersion)
+	}
+
+	if target.MinAuto != nil && testUpdater.currentVersion != nil && testUpdater.currentVersion.LessThan(target.MinAuto) {
+		return fmt.Errorf("refusing to switch to channel %q: current version %s is below required minimum %s", ch, testUpdater.currentVersion, target.MinAuto)
 	}
+
+	testUpdater.channel = ch
+
+	return nil
 }
 
 func (testUpdater *TestUpdater) SetLatestVersionLegacy(version, minAuto *semver.Version) {
 	testUpdater.lock.Lock()
 	defer testUpdater.lock.Unlock()
 
-	testUpdater.latest = updater.VersionInfoLegacy{
+	testUpdater.latest[testUpdater.channel] = updater.VersionInfoLegacy{
 		Version: version,
 		MinAuto: minAuto,
+	}
+
+	testUpdater.rolloutProportion[testUpdater.channel] = 1.0
+}
 
-		RolloutProportion: 1.0,
+// SetLatestVersionForChannel sets the latest version and legacy manifest
+// reported for ch, independently of the currently active channel.
+func (testUpdater *TestUpdater) SetLatestVersionForChannel(ch updater.Channel, version, minAuto *semver.Version) {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	testUpdater.latest[ch] = updater.VersionInfoLegacy{
+		Version: version,
+		MinAuto: minAuto,
+	}
+
+	testUpdater.releases[ch] = updater.VersionInfo{
+		Release: updater.Release{
+			Version: version,
+			MinAuto: minAuto,
+		},
 	}
+
+	testUpdater.rolloutProportion[ch] = 1.0
 }
 
-func (testUpdater *TestUpdater) GetVersionInfoLegacy(_ context.Context, _ updater.Downloader, _ updater.Channel) (updater.VersionInfoLegacy, error) {
+func (testUpdater *TestUpdater) GetVersionInfoLegacy(_ context.Context, _ updater.Downloader, ch updater.Channel) (updater.VersionInfoLegacy, error) {
 	testUpdater.lock.RLock()
 	defer testUpdater.lock.RUnlock()
 
-	return testUpdater.latest, nil
+	info, ok := testUpdater.latest[ch]
+	if !ok {
+		return updater.VersionInfoLegacy{}, fmt.Errorf("no version configured for channel %q", ch)
+	}
+
+	info.RolloutProportion = testUpdater.rolloutProportion[ch]
+
+	return info, nil
+}
+
+// SetInstallError configures the error that InstallUpdate and
+// InstallUpdateLegacy return, so tests can inject an updater.IrrecoverableError
+// (or any other install failure) without a real downloader.
+func (testUpdater *TestUpdater) SetInstallError(err error) {
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	testUpdater.installErr = err
 }
 
 func (testUpdater *TestUpdater) InstallUpdateLegacy(_ context.Context, _ updater.Downloader, _ updater.VersionInfoLegacy) error {
-	return nil
+	testUpdater.lock.RLock()
+	defer testUpdater.lock.RUnlock()
+
+	return testUpdater.installErr
 }
 
 func (testUpdater *TestUpdater) RemoveOldUpdates() error {
 	return nil
 }
 
+// SetLatestVersion sets the latest release for the current channel,
+// including its rollout proportion; use AdvanceRollout afterwards to widen
+// the cohort rather than setting this again.
 func (testUpdater *TestUpdater) SetLatestVersion(releases updater.VersionInfo) {
 	testUpdater.lock.Lock()
 	defer testUpdater.lock.Unlock()
 
-	testUpdater.releases = releases
+	testUpdater.releases[testUpdater.channel] = releases
+	testUpdater.rolloutProportion[testUpdater.channel] = releases.RolloutProportion
+}
+
+// GetVersionInfo coalesces with any other call arriving within the
+// scheduler's throttle window before checking the current channel's latest
+// release.
+func (testUpdater *TestUpdater) GetVersionInfo(ctx context.Context, downloader updater.Downloader) (updater.VersionInfo, error) {
+	return testUpdater.scheduler.GetVersionInfo(ctx, downloader)
 }
 
-func (testUpdater *TestUpdater) GetVersionInfo(_ context.Context, _ updater.Downloader) (updater.VersionInfo, error) {
+func (testUpdater *TestUpdater) getVersionInfo(_ context.Context, _ updater.Downloader) (updater.VersionInfo, error) {
 	testUpdater.lock.RLock()
 	defer testUpdater.lock.RUnlock()
 
-	return testUpdater.releases, nil
+	info := testUpdater.releases[testUpdater.channel]
+	info.RolloutProportion = testUpdater.rolloutProportion[testUpdater.channel]
+
+	return info, nil
 }
 
 func (testUpdater *TestUpdater) InstallUpdate(_ context.Context, _ updater.Downloader, _ updater.Release) error {
-	return nil
+	testUpdater.lock.Lock()
+	defer testUpdater.lock.Unlock()
+
+	testUpdater.installCount++
+
+	return testUpdater.installErr
+}
+
+// InstallCount reports how many times InstallUpdate has been called, so
+// tests can assert whether an install was actually attempted.
+func (testUpdater *TestUpdater) InstallCount() int {
+	testUpdater.lock.RLock()
+	defer testUpdater.lock.RUnlock()
+
+	return testUpdater.installCount
 }
@@ -0,0 +1,51 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+func TestRolloutHoldsBackInstallUntilProportionCrosses(t *testing.T) {
+	current := semver.MustParse("1.0.0")
+	next := semver.MustParse("1.1.0")
+
+	upd := NewTestUpdater(t, current, current)
+	upd.SetThrottle(0)
+	upd.SetLatestVersion(updater.VersionInfo{
+		Release: updater.Release{
+			Version: next,
+			MinAuto: current,
+
+			RolloutProportion: 0,
+		},
+	})
+
+	loop := NewUpdateLoop(upd, nil, nil, time.Hour)
+
+	loop.poll(context.Background())
+
+	if got := upd.InstallCount(); got != 0 {
+		t.Fatalf("install attempted while outside rollout window: count = %d", got)
+	}
+
+	if upd.EligibleForRollout() {
+		t.Fatalf("expected install to be outside the rollout window at proportion 0")
+	}
+
+	upd.AdvanceRollout(1.0)
+
+	if !upd.EligibleForRollout() {
+		t.Fatalf("expected install to be inside the rollout window once proportion reaches 1.0")
+	}
+
+	loop.poll(context.Background())
+
+	if got := upd.InstallCount(); got != 1 {
+		t.Fatalf("expected install to run exactly once after the rollout widened: count = %d", got)
+	}
+}
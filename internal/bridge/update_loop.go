@@ -0,0 +1,147 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+// Reporter is the bridge-wide error/event reporting surface (the same one
+// Mocks.Reporter exercises via ReportMessageWithContext) that the update
+// loop uses to surface an irrecoverable failure to the GUI, instead of
+// inventing a parallel notification path.
+type Reporter interface {
+	ReportMessageWithContext(message string, ctx map[string]any) error
+}
+
+// UpdateLoop periodically checks for and installs updates. If it hits an
+// updater.IrrecoverableError, it cancels itself, reports it through the
+// existing Reporter, and refuses to poll again until Reset is called.
+type UpdateLoop struct {
+	updater    Updater
+	downloader updater.Downloader
+	reporter   Reporter
+
+	period time.Duration
+
+	lock    sync.Mutex
+	runCtx  context.Context
+	cancel  context.CancelFunc
+	running bool
+	stopped bool
+}
+
+func NewUpdateLoop(upd Updater, downloader updater.Downloader, reporter Reporter, period time.Duration) *UpdateLoop {
+	return &UpdateLoop{
+		updater:    upd,
+		downloader: downloader,
+		reporter:   reporter,
+
+		period: period,
+	}
+}
+
+// Start begins polling on a ticker until ctx is cancelled or the loop hits an
+// IrrecoverableError. It is a no-op if the loop is already running, or if it
+// is currently stopped on an irrecoverable error awaiting Reset.
+func (l *UpdateLoop) Start(ctx context.Context) {
+	l.lock.Lock()
+	if l.stopped || l.running {
+		l.lock.Unlock()
+
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	l.runCtx = runCtx
+	l.cancel = cancel
+	l.running = true
+	l.lock.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(l.period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-runCtx.Done():
+				l.lock.Lock()
+				l.running = false
+				l.lock.Unlock()
+
+				return
+			case <-ticker.C:
+				l.poll(runCtx)
+			}
+		}
+	}()
+}
+
+func (l *UpdateLoop) poll(ctx context.Context) {
+	info, err := l.updater.GetVersionInfo(ctx, l.downloader)
+	if err != nil {
+		var irrecoverable *updater.IrrecoverableError
+		if errors.As(err, &irrecoverable) {
+			l.stop(irrecoverable)
+		}
+
+		return
+	}
+
+	// This install is held back until the staged rollout proportion
+	// crosses its bucket, even though a newer release already exists.
+	if !info.EligibleFor(l.updater.RolloutID()) {
+		return
+	}
+
+	if err := l.updater.InstallUpdate(ctx, l.downloader, info.Release); err != nil {
+		var irrecoverable *updater.IrrecoverableError
+		if errors.As(err, &irrecoverable) {
+			l.stop(irrecoverable)
+		}
+	}
+}
+
+func (l *UpdateLoop) stop(reason *updater.IrrecoverableError) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.stopped {
+		return
+	}
+
+	l.stopped = true
+	l.running = false
+
+	if l.cancel != nil {
+		l.cancel()
+	}
+
+	if l.reporter != nil {
+		_ = l.reporter.ReportMessageWithContext("Update failed irrecoverably, giving up until reset", map[string]any{
+			"reason": reason.Reason.String(),
+			"error":  reason.Err,
+		})
+	}
+}
+
+// Reset clears the irrecoverable-stop state so the loop can be started
+// again.
+func (l *UpdateLoop) Reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.stopped = false
+}
+
+// Stopped reports whether the loop has stopped on an irrecoverable error and
+// is awaiting Reset.
+func (l *UpdateLoop) Stopped() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.stopped
+}
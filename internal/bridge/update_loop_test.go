@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+type countingReporter struct {
+	calls int32
+}
+
+func (r *countingReporter) ReportMessageWithContext(_ string, _ map[string]any) error {
+	atomic.AddInt32(&r.calls, 1)
+
+	return nil
+}
+
+func TestUpdateLoopStopsOnceOnIrrecoverableError(t *testing.T) {
+	version := semver.MustParse("1.0.0")
+
+	upd := NewTestUpdater(t, version, version)
+	upd.SetThrottle(0)
+	upd.SetInstallError(&updater.IrrecoverableError{Reason: updater.SignatureInvalid})
+
+	reporter := &countingReporter{}
+	loop := NewUpdateLoop(upd, nil, reporter, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			loop.poll(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if !loop.Stopped() {
+		t.Fatalf("expected the loop to be stopped after an irrecoverable install error")
+	}
+
+	if got := atomic.LoadInt32(&reporter.calls); got != 1 {
+		t.Fatalf("expected the reporter to fire exactly once, got %d", got)
+	}
+
+	loop.Reset()
+
+	if loop.Stopped() {
+		t.Fatalf("expected Reset to clear the stopped state")
+	}
+}
+
+func TestUpdateLoopStartIsIdempotentWhileRunning(t *testing.T) {
+	version := semver.MustParse("1.0.0")
+
+	upd := NewTestUpdater(t, version, version)
+	upd.SetThrottle(0)
+
+	loop := NewUpdateLoop(upd, nil, nil, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loop.Start(ctx)
+
+	loop.lock.Lock()
+	firstDone := loop.runCtx.Done()
+	loop.lock.Unlock()
+
+	// A second Start while already running must be a no-op: it must not
+	// replace the run context (which would leak the first goroutine) or
+	// spawn a second polling goroutine.
+	loop.Start(ctx)
+
+	loop.lock.Lock()
+	secondDone := loop.runCtx.Done()
+	loop.lock.Unlock()
+
+	if firstDone != secondDone {
+		t.Fatalf("expected a repeated Start call while running to be a no-op")
+	}
+}
@@ -0,0 +1,28 @@
+package bridge
+
+import (
+	"context"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+// Updater is the subset of *updater.Updater that bridge depends on, so tests
+// can swap in TestUpdater.
+type Updater interface {
+	GetVersionInfo(ctx context.Context, downloader updater.Downloader) (updater.VersionInfo, error)
+	GetVersionInfoLegacy(ctx context.Context, downloader updater.Downloader, channel updater.Channel) (updater.VersionInfoLegacy, error)
+
+	InstallUpdate(ctx context.Context, downloader updater.Downloader, release updater.Release) error
+	InstallUpdateLegacy(ctx context.Context, downloader updater.Downloader, versionInfo updater.VersionInfoLegacy) error
+
+	RemoveOldUpdates() error
+
+	// SwitchChannel atomically moves the install to channel, re-checking
+	// the channel's latest release and refusing the switch if it would be
+	// a downgrade (unless AllowDowngrade has been set).
+	SwitchChannel(ctx context.Context, channel updater.Channel) error
+
+	// RolloutID returns this install's stable bucket in [0, 1), used to
+	// decide whether it falls inside a release's staged rollout window.
+	RolloutID() float64
+}
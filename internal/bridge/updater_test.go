@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/ProtonMail/proton-bridge/v3/internal/updater"
+)
+
+func TestSwitchChannelRoundTrip(t *testing.T) {
+	current := semver.MustParse("2.0.0")
+	earlyAccess := semver.MustParse("2.1.0-rc1")
+
+	upd := NewTestUpdater(t, current, current)
+	upd.SetThrottle(0)
+	upd.SetLatestVersionForChannel(updater.EarlyAccessChannel, earlyAccess, current)
+
+	ctx := context.Background()
+
+	if err := upd.SwitchChannel(ctx, updater.EarlyAccessChannel); err != nil {
+		t.Fatalf("opting into early-access should succeed: %v", err)
+	}
+
+	info, err := upd.GetVersionInfo(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetVersionInfo: %v", err)
+	}
+
+	if !info.Version.Equal(earlyAccess) {
+		t.Fatalf("expected early-access version %s, got %s", earlyAccess, info.Version)
+	}
+
+	if err := upd.SwitchChannel(ctx, updater.StableChannel); err != nil {
+		t.Fatalf("opting back out to stable should succeed: %v", err)
+	}
+
+	info, err = upd.GetVersionInfo(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetVersionInfo: %v", err)
+	}
+
+	if !info.Version.Equal(current) {
+		t.Fatalf("expected stable version %s after opting out, got %s", current, info.Version)
+	}
+}
+
+func TestSwitchChannelRefusesDowngrade(t *testing.T) {
+	current := semver.MustParse("2.0.0")
+	older := semver.MustParse("1.9.0")
+
+	upd := NewTestUpdater(t, current, current)
+	upd.SetLatestVersionForChannel(updater.EarlyAccessChannel, older, current)
+
+	if err := upd.SwitchChannel(context.Background(), updater.EarlyAccessChannel); err == nil {
+		t.Fatalf("expected switch to an older release to be refused")
+	}
+
+	upd.SetAllowDowngrade(true)
+
+	if err := upd.SwitchChannel(context.Background(), updater.EarlyAccessChannel); err != nil {
+		t.Fatalf("expected switch to succeed once downgrades are allowed: %v", err)
+	}
+}
+
+func TestSwitchChannelRefusesBelowMinAuto(t *testing.T) {
+	current := semver.MustParse("2.0.0")
+	early := semver.MustParse("2.1.0")
+	minAuto := semver.MustParse("2.0.1")
+
+	upd := NewTestUpdater(t, current, current)
+	upd.SetLatestVersionForChannel(updater.EarlyAccessChannel, early, minAuto)
+
+	if err := upd.SwitchChannel(context.Background(), updater.EarlyAccessChannel); err == nil {
+		t.Fatalf("expected switch to be refused when current version is below the channel's MinAuto")
+	}
+}
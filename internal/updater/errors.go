@@ -0,0 +1,50 @@
+package updater
+
+import "fmt"
+
+// IrrecoverableReason identifies why an update failure cannot be fixed by
+// retrying.
+type IrrecoverableReason int
+
+const (
+	SignatureInvalid IrrecoverableReason = iota
+	ManifestTampered
+	DiskFull
+	ChannelRevoked
+)
+
+func (r IrrecoverableReason) String() string {
+	switch r {
+	case SignatureInvalid:
+		return "signature invalid"
+	case ManifestTampered:
+		return "manifest tampered"
+	case DiskFull:
+		return "disk full"
+	case ChannelRevoked:
+		return "channel revoked"
+	default:
+		return "unknown"
+	}
+}
+
+// IrrecoverableError is returned by InstallUpdate/InstallUpdateLegacy when an
+// update failure cannot be fixed by retrying, such as a signature that fails
+// verification or a release key that has been revoked. Callers must stop
+// polling for updates until the condition is addressed.
+type IrrecoverableError struct {
+	Reason IrrecoverableReason
+	Err    error
+}
+
+func (e *IrrecoverableError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("irrecoverable update error (%s): %v", e.Reason, e.Err)
+	}
+
+	return fmt.Sprintf("irrecoverable update error (%s)", e.Reason)
+}
+
+func (e *IrrecoverableError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,27 @@
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// RolloutID derives a stable, uniformly distributed bucket in [0, 1) for the
+// given seed, so a staged rollout can decide whether an install falls inside
+// the rollout window without re-randomizing on every check.
+func RolloutID(seed string) float64 {
+	sum := sha256.Sum256([]byte(seed))
+
+	return float64(binary.BigEndian.Uint32(sum[:4])) / float64(1<<32)
+}
+
+// EligibleFor reports whether an install with the given rollout ID is inside
+// this release's rollout window.
+func (r Release) EligibleFor(rolloutID float64) bool {
+	return rolloutID < r.RolloutProportion
+}
+
+// EligibleFor reports whether an install with the given rollout ID is inside
+// this version's rollout window.
+func (v VersionInfoLegacy) EligibleFor(rolloutID float64) bool {
+	return rolloutID < v.RolloutProportion
+}
@@ -0,0 +1,110 @@
+package updater
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultThrottle = 2 * time.Second
+
+// CheckScheduler coalesces version checks that arrive within a short window
+// into a single round-trip, so bursts of triggers (periodic timer,
+// network-reconnect, manual "check for updates", account add) share one
+// result instead of each firing its own request.
+type CheckScheduler struct {
+	check func(ctx context.Context, downloader Downloader) (VersionInfo, error)
+
+	lock     sync.Mutex
+	throttle time.Duration
+	pending  *pendingCheck
+	calls    int
+}
+
+type pendingCheck struct {
+	timer  *time.Timer
+	done   chan struct{}
+	result VersionInfo
+	err    error
+}
+
+// NewCheckScheduler wraps check (typically an Updater's GetVersionInfo) with
+// coalescing: calls arriving within the throttle window collapse into one
+// invocation of check, and every caller in the window receives its result.
+func NewCheckScheduler(check func(ctx context.Context, downloader Downloader) (VersionInfo, error)) *CheckScheduler {
+	return &CheckScheduler{
+		check:    check,
+		throttle: defaultThrottle,
+	}
+}
+
+// SetThrottle changes the coalescing window for subsequent checks.
+func (s *CheckScheduler) SetThrottle(d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.throttle = d
+}
+
+// CheckCallCount reports how many times the wrapped check has actually been
+// invoked, so tests can assert that coalescing took place.
+func (s *CheckScheduler) CheckCallCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.calls
+}
+
+// GetVersionInfo requests a version check, coalescing with any check already
+// pending within the throttle window. All callers within the window observe
+// the same result.
+func (s *CheckScheduler) GetVersionInfo(ctx context.Context, downloader Downloader) (VersionInfo, error) {
+	s.lock.Lock()
+	pending := s.pending
+	if pending == nil {
+		pending = &pendingCheck{done: make(chan struct{})}
+		s.pending = pending
+		pending.timer = time.AfterFunc(s.throttle, func() { s.fire(downloader) })
+	}
+	s.lock.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.result, pending.err
+	case <-ctx.Done():
+		return VersionInfo{}, ctx.Err()
+	}
+}
+
+func (s *CheckScheduler) fire(downloader Downloader) {
+	s.lock.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.lock.Unlock()
+
+	if pending == nil {
+		return
+	}
+
+	s.lock.Lock()
+	s.calls++
+	s.lock.Unlock()
+
+	pending.result, pending.err = s.check(context.Background(), downloader)
+	close(pending.done)
+}
+
+// FlushPending fires any coalesced check immediately instead of waiting for
+// the throttle window to elapse, so tests can assert deterministically.
+func (s *CheckScheduler) FlushPending(downloader Downloader) {
+	s.lock.Lock()
+	pending := s.pending
+	s.lock.Unlock()
+
+	if pending == nil {
+		return
+	}
+
+	pending.timer.Stop()
+	s.fire(downloader)
+}
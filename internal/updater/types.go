@@ -0,0 +1,43 @@
+package updater
+
+import (
+	"github.com/Masterminds/semver/v3"
+)
+
+// Channel identifies a release track that an install can subscribe to.
+type Channel string
+
+const (
+	StableChannel      Channel = "stable"
+	EarlyAccessChannel Channel = "early-access"
+)
+
+// Downloader fetches and verifies update artifacts.
+type Downloader interface {
+	DownloadAndVerify(url, sig string) ([]byte, error)
+}
+
+// Release describes a single downloadable update package, gated by a staged
+// rollout.
+type Release struct {
+	Version *semver.Version
+	MinAuto *semver.Version
+
+	// RolloutProportion is the fraction (0..1) of installs that are
+	// currently eligible for this release.
+	RolloutProportion float64
+}
+
+// VersionInfo is the result of a version check against a channel.
+type VersionInfo struct {
+	Release
+}
+
+// VersionInfoLegacy is the manifest shape understood by clients that predate
+// VersionInfo and staged rollouts.
+type VersionInfoLegacy struct {
+	Version *semver.Version
+	MinAuto *semver.Version
+
+	RolloutProportion float64
+}
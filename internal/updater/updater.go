@@ -0,0 +1,208 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	manifestPath    = "version.json"
+	manifestSigPath = "version.json.sig"
+)
+
+// Installer applies a downloaded update package and prunes old installs. It
+// is platform-specific and implemented outside this package.
+type Installer interface {
+	Install(data []byte) error
+	RemoveOldUpdates() error
+}
+
+// Updater is the production auto-update client: it checks the repository for
+// the latest release on the current channel, can move the install to a
+// different channel, and installs updates through a Downloader and Installer.
+type Updater struct {
+	repository     string
+	downloader     Downloader
+	installer      Installer
+	currentVersion *semver.Version
+	rolloutID      float64
+
+	lock           sync.RWMutex
+	channel        Channel
+	allowDowngrade bool
+
+	// scheduler coalesces bursts of GetVersionInfo calls (periodic timer,
+	// network-reconnect, manual "check for updates") into one round-trip.
+	scheduler *CheckScheduler
+}
+
+// New returns an Updater for repository (the base URL manifests are served
+// from), tracking currentVersion and bucketed into the staged rollout by
+// rolloutSeed (typically a stable per-install identifier). downloader is used
+// for calls, such as SwitchChannel, that the bridge.Updater interface doesn't
+// thread a per-call downloader through.
+func New(repository string, downloader Downloader, installer Installer, currentVersion *semver.Version, rolloutSeed string) *Updater {
+	u := &Updater{
+		repository:     repository,
+		downloader:     downloader,
+		installer:      installer,
+		currentVersion: currentVersion,
+		rolloutID:      RolloutID(rolloutSeed),
+		channel:        StableChannel,
+	}
+
+	u.scheduler = NewCheckScheduler(u.checkCurrentChannel)
+
+	return u
+}
+
+// RolloutID returns this install's stable bucket in [0, 1).
+func (u *Updater) RolloutID() float64 {
+	return u.rolloutID
+}
+
+// SetAllowDowngrade toggles whether SwitchChannel may move to a channel whose
+// latest release is older than the current version.
+func (u *Updater) SetAllowDowngrade(allow bool) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.allowDowngrade = allow
+}
+
+func (u *Updater) currentChannel() Channel {
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+
+	return u.channel
+}
+
+// GetVersionInfo checks the current channel for its latest release,
+// coalescing with any other call arriving within the scheduler's throttle
+// window.
+func (u *Updater) GetVersionInfo(ctx context.Context, downloader Downloader) (VersionInfo, error) {
+	return u.scheduler.GetVersionInfo(ctx, downloader)
+}
+
+func (u *Updater) checkCurrentChannel(_ context.Context, downloader Downloader) (VersionInfo, error) {
+	return u.fetchChannel(downloader, u.currentChannel())
+}
+
+// GetVersionInfoLegacy checks channel for its latest release in the legacy
+// manifest shape.
+func (u *Updater) GetVersionInfoLegacy(_ context.Context, downloader Downloader, channel Channel) (VersionInfoLegacy, error) {
+	info, err := u.fetchChannel(downloader, channel)
+	if err != nil {
+		return VersionInfoLegacy{}, err
+	}
+
+	return VersionInfoLegacy{
+		Version:           info.Version,
+		MinAuto:           info.MinAuto,
+		RolloutProportion: info.RolloutProportion,
+	}, nil
+}
+
+// manifest is the JSON shape served at <repository>/<channel>/version.json.
+type manifest struct {
+	Version           string  `json:"Version"`
+	MinAuto           string  `json:"MinAuto"`
+	RolloutProportion float64 `json:"RolloutProportion"`
+}
+
+func (u *Updater) fetchChannel(downloader Downloader, ch Channel) (VersionInfo, error) {
+	raw, err := downloader.DownloadAndVerify(
+		fmt.Sprintf("%s/%s/%s", u.repository, ch, manifestPath),
+		fmt.Sprintf("%s/%s/%s", u.repository, ch, manifestSigPath),
+	)
+	if err != nil {
+		return VersionInfo{}, fmt.Errorf("failed to fetch manifest for channel %q: %w", ch, err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return VersionInfo{}, &IrrecoverableError{Reason: ManifestTampered, Err: err}
+	}
+
+	version, err := semver.NewVersion(m.Version)
+	if err != nil {
+		return VersionInfo{}, &IrrecoverableError{Reason: ManifestTampered, Err: err}
+	}
+
+	minAuto, err := semver.NewVersion(m.MinAuto)
+	if err != nil {
+		return VersionInfo{}, &IrrecoverableError{Reason: ManifestTampered, Err: err}
+	}
+
+	return VersionInfo{
+		Release: Release{
+			Version:           version,
+			MinAuto:           minAuto,
+			RolloutProportion: m.RolloutProportion,
+		},
+	}, nil
+}
+
+// SwitchChannel moves the install to ch, refusing to do so if ch's latest
+// release is older than the current version (unless AllowDowngrade was set)
+// or if the current version is below ch's MinAuto.
+func (u *Updater) SwitchChannel(_ context.Context, ch Channel) error {
+	target, err := u.fetchChannel(u.downloader, ch)
+	if err != nil {
+		return err
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if !u.allowDowngrade && target.Version != nil && u.currentVersion != nil && target.Version.LessThan(u.currentVersion) {
+		return fmt.Errorf("refusing to switch to channel %q: %s would be a downgrade from %s", ch, target.Version, u.currentVersion)
+	}
+
+	if target.MinAuto != nil && u.currentVersion != nil && u.currentVersion.LessThan(target.MinAuto) {
+		return fmt.Errorf("refusing to switch to channel %q: current version %s is below required minimum %s", ch, u.currentVersion, target.MinAuto)
+	}
+
+	u.channel = ch
+
+	return nil
+}
+
+// InstallUpdate downloads and installs release. Every failure is returned as
+// an IrrecoverableError: a failed download or verification with reason
+// SignatureInvalid, a failed install with reason DiskFull. Transient failures
+// are not told apart from permanent ones.
+func (u *Updater) InstallUpdate(_ context.Context, downloader Downloader, release Release) error {
+	base := fmt.Sprintf("%s/%s/bridge-%s", u.repository, u.currentChannel(), release.Version)
+
+	data, err := downloader.DownloadAndVerify(base+".pkg", base+".pkg.sig")
+	if err != nil {
+		return &IrrecoverableError{Reason: SignatureInvalid, Err: err}
+	}
+
+	if err := u.installer.Install(data); err != nil {
+		return &IrrecoverableError{Reason: DiskFull, Err: err}
+	}
+
+	return nil
+}
+
+// InstallUpdateLegacy installs versionInfo the same way InstallUpdate does,
+// for clients still on the legacy manifest shape.
+func (u *Updater) InstallUpdateLegacy(ctx context.Context, downloader Downloader, versionInfo VersionInfoLegacy) error {
+	return u.InstallUpdate(ctx, downloader, Release{
+		Version:           versionInfo.Version,
+		MinAuto:           versionInfo.MinAuto,
+		RolloutProportion: versionInfo.RolloutProportion,
+	})
+}
+
+// RemoveOldUpdates prunes installed update packages that are no longer
+// needed.
+func (u *Updater) RemoveOldUpdates() error {
+	return u.installer.RemoveOldUpdates()
+}